@@ -0,0 +1,164 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// tokenRe splits a packer-style boot command string into literal runs and
+// <token> directives, e.g. "root<enter><wait5>" -> ["root", "<enter>", "<wait5>"].
+var tokenRe = regexp.MustCompile(`<[^<>]+>|[^<]+`)
+
+// specialKeys maps packer token names to QEMU monitor `sendkey` key names.
+var specialKeys = map[string]string{
+	"enter":    "ret",
+	"return":   "ret",
+	"esc":      "esc",
+	"tab":      "tab",
+	"spacebar": "spc",
+	"bs":       "backspace",
+	"del":      "delete",
+	"f1":       "f1", "f2": "f2", "f3": "f3", "f4": "f4",
+	"f5": "f5", "f6": "f6", "f7": "f7", "f8": "f8",
+	"f9": "f9", "f10": "f10", "f11": "f11", "f12": "f12",
+	"up": "up", "down": "down", "left": "left", "right": "right",
+	"pageup": "pgup", "pagedown": "pgdn",
+	"home": "home", "end": "end",
+	"insert": "insert",
+}
+
+// waitRe matches <wait>, <wait5>, <wait10s>, <wait1m> etc.
+var waitRe = regexp.MustCompile(`^wait(\d*)(s|m)?$`)
+
+// KeySender is the subset of Driver needed to type a boot command; it is
+// satisfied by *proxmox.Client via ProxmoxDriver.
+type KeySender interface {
+	SendKey(ctx context.Context, key string) error
+}
+
+// TypeBootCommand translates a packer-style boot command into a sequence of
+// QEMU monitor sendkey calls, sleeping on <wait> tokens and waiting
+// interval between keystrokes so the guest's input buffer can keep up.
+func TypeBootCommand(ctx context.Context, sender KeySender, command string, interval time.Duration) error {
+	// heldModifiers tracks modifier keys toggled on by <leftCtrlOn> style
+	// tokens until their matching <leftCtrlOff> token, for sequences like
+	// <leftCtrlOn>x<leftCtrlOff>. Scoped to this call so concurrent or
+	// successive builds never see another invocation's held modifiers.
+	heldModifiers := map[string]bool{}
+
+	for _, tok := range tokenRe.FindAllString(command, -1) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if strings.HasPrefix(tok, "<") && strings.HasSuffix(tok, ">") {
+			name := strings.ToLower(tok[1 : len(tok)-1])
+			if d, ok := waitDuration(name); ok {
+				time.Sleep(d)
+				continue
+			}
+			if err := sendSpecial(ctx, sender, name, interval, heldModifiers); err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, r := range tok {
+			if err := sendRune(ctx, sender, r, heldModifiers); err != nil {
+				return err
+			}
+			time.Sleep(interval)
+		}
+	}
+	return nil
+}
+
+func waitDuration(name string) (time.Duration, bool) {
+	m := waitRe.FindStringSubmatch(name)
+	if m == nil {
+		return 0, false
+	}
+	if m[1] == "" {
+		return time.Second, true
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil {
+		return 0, false
+	}
+	if m[2] == "m" {
+		return time.Duration(n) * time.Minute, true
+	}
+	return time.Duration(n) * time.Second, true
+}
+
+// sendSpecial handles multi-key tokens like <leftCtrlOn>x<leftCtrlOff> by
+// recording held-modifier names on heldModifiers; everything else is a
+// single named key.
+func sendSpecial(ctx context.Context, sender KeySender, name string, interval time.Duration, heldModifiers map[string]bool) error {
+	switch name {
+	case "leftctrlon", "leftalton", "leftshifton":
+		heldModifiers[strings.TrimSuffix(name, "on")] = true
+		return nil
+	case "leftctrloff", "leftaltoff", "leftshiftoff":
+		delete(heldModifiers, strings.TrimSuffix(name, "off"))
+		return nil
+	}
+
+	key, ok := specialKeys[name]
+	if !ok {
+		return fmt.Errorf("unknown boot command token <%s>", name)
+	}
+	time.Sleep(interval)
+	return sender.SendKey(ctx, withHeldModifiers(key, heldModifiers))
+}
+
+func withHeldModifiers(key string, heldModifiers map[string]bool) string {
+	for mod := range heldModifiers {
+		key = strings.TrimPrefix(mod, "left") + "-" + key
+	}
+	return key
+}
+
+func sendRune(ctx context.Context, sender KeySender, r rune, heldModifiers map[string]bool) error {
+	key, shifted := runeToSendKey(r)
+	if shifted {
+		key = "shift-" + key
+	}
+	return sender.SendKey(ctx, withHeldModifiers(key, heldModifiers))
+}
+
+// runeToSendKey maps a single typed character to the QEMU monitor sendkey
+// name, reporting whether the shift modifier is required to produce it.
+func runeToSendKey(r rune) (string, bool) {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return string(r), false
+	case r >= 'A' && r <= 'Z':
+		return strings.ToLower(string(r)), true
+	case r >= '0' && r <= '9':
+		return string(r), false
+	}
+
+	if key, ok := shiftedSymbols[r]; ok {
+		return key, true
+	}
+	if key, ok := plainSymbols[r]; ok {
+		return key, false
+	}
+	return string(r), false
+}
+
+var plainSymbols = map[rune]string{
+	' ': "spc", '-': "minus", '=': "equal", '[': "bracket_left", ']': "bracket_right",
+	';': "semicolon", '\'': "apostrophe", ',': "comma", '.': "dot", '/': "slash", '`': "grave_accent",
+}
+
+var shiftedSymbols = map[rune]string{
+	'!': "1", '@': "2", '#': "3", '$': "4", '%': "5", '^': "6", '&': "7", '*': "8", '(': "9", ')': "0",
+	'_': "minus", '+': "equal", '{': "bracket_left", '}': "bracket_right",
+	':': "semicolon", '"': "apostrophe", '<': "comma", '>': "dot", '?': "slash", '~': "grave_accent",
+}
@@ -0,0 +1,117 @@
+package builder
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// recordingSender is a KeySender that records every key it's asked to send.
+type recordingSender struct {
+	keys []string
+}
+
+func (s *recordingSender) SendKey(ctx context.Context, key string) error {
+	s.keys = append(s.keys, key)
+	return nil
+}
+
+func TestWaitDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		want time.Duration
+		ok   bool
+	}{
+		{"wait", time.Second, true},
+		{"wait5", 5 * time.Second, true},
+		{"wait1m", time.Minute, true},
+		{"wait10s", 10 * time.Second, true},
+		{"enter", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := waitDuration(tt.name)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("waitDuration(%q) = %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRuneToSendKey(t *testing.T) {
+	tests := []struct {
+		r       rune
+		wantKey string
+		wantShift bool
+	}{
+		{'a', "a", false},
+		{'A', "a", true},
+		{'5', "5", false},
+		{'!', "1", true},
+		{'-', "minus", false},
+	}
+
+	for _, tt := range tests {
+		key, shifted := runeToSendKey(tt.r)
+		if key != tt.wantKey || shifted != tt.wantShift {
+			t.Errorf("runeToSendKey(%q) = %q, %v; want %q, %v", tt.r, key, shifted, tt.wantKey, tt.wantShift)
+		}
+	}
+}
+
+func TestTypeBootCommand(t *testing.T) {
+	sender := &recordingSender{}
+
+	// <wait0> exercises the same token-parsing path as <wait5>/<wait1m>
+	// without actually sleeping the test.
+	err := TypeBootCommand(context.Background(), sender, "aA<wait0>b", time.Millisecond)
+	if err != nil {
+		t.Fatalf("TypeBootCommand returned error: %v", err)
+	}
+
+	want := []string{"a", "shift-a", "b"}
+	if !equalStrings(sender.keys, want) {
+		t.Errorf("sent keys = %v, want %v", sender.keys, want)
+	}
+}
+
+func TestTypeBootCommandHeldModifier(t *testing.T) {
+	sender := &recordingSender{}
+
+	if err := TypeBootCommand(context.Background(), sender, "<leftCtrlOn>x<leftCtrlOff>y", time.Millisecond); err != nil {
+		t.Fatalf("TypeBootCommand returned error: %v", err)
+	}
+
+	want := []string{"ctrl-x", "y"}
+	if !equalStrings(sender.keys, want) {
+		t.Errorf("sent keys = %v, want %v", sender.keys, want)
+	}
+}
+
+func TestTypeBootCommandHeldModifierDoesNotLeak(t *testing.T) {
+	first := &recordingSender{}
+	if err := TypeBootCommand(context.Background(), first, "<leftCtrlOn>x", time.Millisecond); err != nil {
+		t.Fatalf("TypeBootCommand returned error: %v", err)
+	}
+
+	second := &recordingSender{}
+	if err := TypeBootCommand(context.Background(), second, "y", time.Millisecond); err != nil {
+		t.Fatalf("TypeBootCommand returned error: %v", err)
+	}
+
+	want := []string{"y"}
+	if !equalStrings(second.keys, want) {
+		t.Errorf("held modifier leaked across invocations: sent keys = %v, want %v", second.keys, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
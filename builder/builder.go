@@ -0,0 +1,89 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+// defaultKeystrokeInterval is how long to wait between individual keystrokes
+// sent to the guest, giving BIOS/bootloader input buffers time to keep up.
+const defaultKeystrokeInterval = 100 * time.Millisecond
+
+// Build provisions a VM from cfg end-to-end: create, start, type the boot
+// command, optionally serve http_directory for the installer, wait for SSH,
+// run provisioners, then convert the guest to a template. It returns the
+// resulting template's VMID.
+func Build(ctx context.Context, client *proxmox.Client, cfg *Config) (int, error) {
+	interval := defaultKeystrokeInterval
+	if cfg.KeystrokeInterval != "" {
+		d, err := time.ParseDuration(cfg.KeystrokeInterval)
+		if err != nil {
+			return 0, fmt.Errorf("invalid keystroke_interval %q: %w", cfg.KeystrokeInterval, err)
+		}
+		interval = d
+	}
+
+	bootCommand := strings.Join(cfg.BootCommand, "")
+	if cfg.HTTPDirectory != "" {
+		addr, shutdown, err := ServeDirectory(cfg.HTTPDirectory, cfg.HTTPPort, cfg.HTTPHost)
+		if err != nil {
+			return 0, err
+		}
+		defer shutdown(context.Background())
+		bootCommand = strings.ReplaceAll(bootCommand, "{{ .HTTPIP }}:{{ .HTTPPort }}", addr)
+	}
+
+	if err := client.CreateQemuCtx(ctx, cfg.TargetNode, cfg.VMID, proxmox.QemuCreateOptions{
+		Name:   cfg.TemplateName,
+		Ide2:   cfg.ISO + ",media=cdrom",
+		Scsi0:  cfg.Disk,
+		Net0:   cfg.Network,
+		Cores:  cfg.Cores,
+		Memory: cfg.Memory,
+	}); err != nil {
+		return 0, fmt.Errorf("creating vm: %w", err)
+	}
+
+	vmr, err := client.GetVmRefByNameCtx(ctx, cfg.TemplateName)
+	if err != nil {
+		return 0, fmt.Errorf("resolving created vm: %w", err)
+	}
+
+	driver := NewProxmoxDriver(client, vmr)
+
+	if err := driver.StartVM(ctx); err != nil {
+		return 0, fmt.Errorf("starting vm: %w", err)
+	}
+
+	if bootCommand != "" {
+		if err := driver.TypeBootCommand(ctx, bootCommand, interval); err != nil {
+			return 0, fmt.Errorf("typing boot command: %w", err)
+		}
+	}
+
+	if cfg.SSHUsername != "" {
+		sshClient, err := WaitForSSH(ctx, cfg.SSHHost, cfg.SSHPort, cfg.SSHUsername, cfg.SSHPassword)
+		if err != nil {
+			return 0, fmt.Errorf("waiting for guest to come up: %w", err)
+		}
+		defer sshClient.Close()
+
+		if err := RunProvisioners(sshClient, cfg.Provisioners); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := driver.StopVM(ctx); err != nil {
+		return 0, fmt.Errorf("stopping vm: %w", err)
+	}
+
+	if err := driver.ConvertToTemplate(ctx); err != nil {
+		return 0, fmt.Errorf("converting vm to template: %w", err)
+	}
+
+	return vmr.VmId, nil
+}
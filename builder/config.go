@@ -0,0 +1,71 @@
+// Package builder drives a single VM through create, boot-command typing,
+// provisioning and templating, the way Packer's QEMU builder does for
+// Proxmox guests.
+package builder
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2/hclsimple"
+)
+
+// Config describes the VM to provision and how to reach it once it boots.
+type Config struct {
+	TargetNode   string   `hcl:"target_node" json:"target_node"`
+	VMID         int      `hcl:"vmid" json:"vmid"`
+	TemplateName string   `hcl:"template_name" json:"template_name"`
+	ISO          string   `hcl:"iso" json:"iso"`
+	Disk         string   `hcl:"disk" json:"disk"`
+	Network      string   `hcl:"network" json:"network"`
+	Cores        int      `hcl:"cores" json:"cores"`
+	Memory       int      `hcl:"memory" json:"memory"`
+	BootCommand  []string `hcl:"boot_command" json:"boot_command"`
+
+	HTTPDirectory string `hcl:"http_directory,optional" json:"http_directory,omitempty"`
+	HTTPPort      int    `hcl:"http_port,optional" json:"http_port,omitempty"`
+	// HTTPHost overrides the address advertised to the guest for
+	// http_directory. On a multi-homed host (docker0, VPN, secondary NICs),
+	// the address auto-detected from the host's interfaces may not be
+	// reachable from the VM's network; set this to the address of the
+	// interface on the bridge the VM is attached to.
+	HTTPHost string `hcl:"http_host,optional" json:"http_host,omitempty"`
+
+	SSHHost     string `hcl:"ssh_host" json:"ssh_host"`
+	SSHUsername string `hcl:"ssh_username" json:"ssh_username"`
+	SSHPassword string `hcl:"ssh_password,optional" json:"ssh_password,omitempty"`
+	SSHPort     int    `hcl:"ssh_port,optional" json:"ssh_port,omitempty"`
+
+	Provisioners []string `hcl:"provisioners,optional" json:"provisioners,omitempty"`
+
+	KeystrokeInterval string `hcl:"keystroke_interval,optional" json:"keystroke_interval,omitempty"`
+}
+
+// LoadConfig reads a builder config from an HCL or JSON file, selected by
+// its extension.
+func LoadConfig(path string) (*Config, error) {
+	var cfg Config
+	switch filepath.Ext(path) {
+	case ".json":
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	case ".hcl":
+		if err := hclsimple.DecodeFile(path, nil, &cfg); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config extension %q (want .hcl or .json)", filepath.Ext(path))
+	}
+
+	if cfg.SSHPort == 0 {
+		cfg.SSHPort = 22
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,16 @@
+package builder
+
+import (
+	"context"
+	"time"
+)
+
+// Driver drives a single guest through the steps a template build needs.
+// It exists as an interface so builds can be scripted programmatically
+// against something other than a live Proxmox cluster (tests, dry-runs).
+type Driver interface {
+	StartVM(ctx context.Context) error
+	StopVM(ctx context.Context) error
+	TypeBootCommand(ctx context.Context, command string, interval time.Duration) error
+	ConvertToTemplate(ctx context.Context) error
+}
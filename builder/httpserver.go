@@ -0,0 +1,70 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// ServeDirectory serves dir over HTTP on the given port (0 picks a free
+// port), bound to all interfaces so a guest on any network the host is
+// attached to can reach it for kickstart/preseed files referenced by the
+// boot command. It returns the address to advertise to the guest (not the
+// wildcard bind address, which guests cannot dial) and a func to shut the
+// server down.
+//
+// host, if non-empty, is advertised as-is: on a multi-homed host (docker0,
+// VPN, secondary NICs) the first routable interface found by auto-detection
+// may not be the one the VM's bridge is actually reachable on, so callers
+// that know the right address (Config.HTTPHost) should pass it explicitly.
+// If host is empty, ServeDirectory falls back to guessing via
+// routableHostIP.
+func ServeDirectory(dir string, port int, host string) (addr string, shutdown func(context.Context) error, err error) {
+	ln, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%d", port))
+	if err != nil {
+		return "", nil, fmt.Errorf("binding http directory server: %w", err)
+	}
+
+	if host == "" {
+		host, err = routableHostIP()
+		if err != nil {
+			ln.Close()
+			return "", nil, fmt.Errorf("finding a guest-reachable address for the http directory server: %w", err)
+		}
+	}
+
+	srv := &http.Server{Handler: http.FileServer(http.Dir(dir))}
+	go srv.Serve(ln)
+
+	_, boundPort, err := net.SplitHostPort(ln.Addr().String())
+	if err != nil {
+		ln.Close()
+		return "", nil, err
+	}
+
+	return net.JoinHostPort(host, boundPort), srv.Shutdown, nil
+}
+
+// routableHostIP returns the host's first non-loopback, non-link-local IPv4
+// address, i.e. the address a guest on the LAN or the bridged VM network
+// could dial the host at.
+func routableHostIP() (string, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", err
+	}
+
+	for _, a := range addrs {
+		ipNet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip := ipNet.IP.To4()
+		if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		return ip.String(), nil
+	}
+	return "", fmt.Errorf("no routable IPv4 address found on this host")
+}
@@ -0,0 +1,41 @@
+package builder
+
+import (
+	"context"
+	"time"
+
+	"github.com/Telmate/proxmox-api-go/proxmox"
+)
+
+// ProxmoxDriver implements Driver against a live Proxmox guest.
+type ProxmoxDriver struct {
+	Client *proxmox.Client
+	Vmr    *proxmox.VmRef
+}
+
+// NewProxmoxDriver returns a Driver bound to the given guest.
+func NewProxmoxDriver(client *proxmox.Client, vmr *proxmox.VmRef) *ProxmoxDriver {
+	return &ProxmoxDriver{Client: client, Vmr: vmr}
+}
+
+func (d *ProxmoxDriver) StartVM(ctx context.Context) error {
+	return d.Client.StartVmCtx(ctx, d.Vmr, proxmox.VMStartOptions{})
+}
+
+func (d *ProxmoxDriver) StopVM(ctx context.Context) error {
+	return d.Client.StopVmCtx(ctx, d.Vmr)
+}
+
+func (d *ProxmoxDriver) ConvertToTemplate(ctx context.Context) error {
+	return d.Client.ConvertToTemplateCtx(ctx, d.Vmr)
+}
+
+// SendKey implements KeySender by forwarding a single QEMU monitor sendkey
+// command for the guest.
+func (d *ProxmoxDriver) SendKey(ctx context.Context, key string) error {
+	return d.Client.MonitorCmdCtx(ctx, d.Vmr, "sendkey "+key)
+}
+
+func (d *ProxmoxDriver) TypeBootCommand(ctx context.Context, command string, interval time.Duration) error {
+	return TypeBootCommand(ctx, d, command, interval)
+}
@@ -0,0 +1,56 @@
+package builder
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// WaitForSSH polls host:port until it accepts an SSH connection and
+// authenticates, or ctx is cancelled.
+func WaitForSSH(ctx context.Context, host string, port int, username, password string) (*ssh.Client, error) {
+	addr := net.JoinHostPort(host, fmt.Sprintf("%d", port))
+	config := &ssh.ClientConfig{
+		User:            username,
+		Auth:            []ssh.AuthMethod{ssh.Password(password)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	}
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		client, err := ssh.Dial("tcp", addr, config)
+		if err == nil {
+			return client, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for ssh on %s: %w", addr, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunProvisioners runs each command as an inline shell provisioner in its
+// own SSH session on client, in order, stopping at the first failure.
+func RunProvisioners(client *ssh.Client, commands []string) error {
+	for _, cmd := range commands {
+		session, err := client.NewSession()
+		if err != nil {
+			return fmt.Errorf("opening ssh session: %w", err)
+		}
+
+		output, err := session.CombinedOutput(cmd)
+		session.Close()
+		if err != nil {
+			return fmt.Errorf("provisioner %q failed: %w\n%s", cmd, err, output)
+		}
+	}
+	return nil
+}
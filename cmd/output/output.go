@@ -0,0 +1,74 @@
+// Package output defines the structured records the CLI's `list` commands
+// render, and the text/json/yaml formats they can be rendered in.
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Format selects how a list command renders its results.
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+	YAML Format = "yaml"
+)
+
+// ParseFormat validates a --output flag value.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case Text, JSON, YAML:
+		return Format(s), nil
+	default:
+		return "", fmt.Errorf("unknown output format %q (want text, json or yaml)", s)
+	}
+}
+
+// NodeSummary is the structured view of a cluster node.
+type NodeSummary struct {
+	Name   string                 `json:"name"`
+	Status string                 `json:"status,omitempty"`
+	Attrs  map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// StorageSummary is the structured view of a storage volume on a node.
+type StorageSummary struct {
+	Node  string                 `json:"node"`
+	Name  string                 `json:"name"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// VMSummary is the structured view of a guest, including its config and
+// (if the guest agent responded) its reported network interfaces.
+type VMSummary struct {
+	Name                   string                 `json:"name"`
+	Status                 map[string]interface{} `json:"status,omitempty"`
+	Config                 map[string]interface{} `json:"config,omitempty"`
+	AgentNetworkInterfaces []interface{}          `json:"agentNetworkInterfaces,omitempty"`
+}
+
+// Write renders v in the given format to w. Text formatting is delegated to
+// textFn, since each list command has its own human-readable layout.
+func Write(w io.Writer, format Format, v interface{}, textFn func(io.Writer, interface{})) error {
+	switch format {
+	case JSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case YAML:
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	default:
+		textFn(w, v)
+		return nil
+	}
+}
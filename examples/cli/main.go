@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
+	"github.com/Telmate/proxmox-api-go/builder"
+	"github.com/Telmate/proxmox-api-go/cmd/output"
 	"github.com/Telmate/proxmox-api-go/proxmox"
 )
 
@@ -17,9 +23,21 @@ Usage:
   %s [options] <command> [<args>...]
 
   Commands:
-	help [command]     Show help for a command
-	list [object type] List objects of a given type (e.g. cluster, node, storage, vm, ...)
-	login              Login to Proxmox server and display credentials (not necessary for most commands)
+	help [command]        Show help for a command
+	list [object type]    List objects of a given type (e.g. cluster, node, storage, vm, lxc, ...)
+	login                 Login to Proxmox server and display credentials (not necessary for most commands)
+	create lxc [flags]    Create an LXC container
+	destroy lxc <name>    Destroy an LXC container
+	start lxc <name>      Start an LXC container
+	stop lxc <name>       Stop an LXC container
+	start vm <name>       Start a VM (--machine, --force-cpu, --timeout, ...)
+	stop vm <name>        Stop a VM
+	reboot vm <name>      Reboot a VM (--timeout)
+	resume vm <name>      Resume a suspended VM (--no-check, --skip-lock)
+	shutdown vm <name>    Gracefully shut down a VM (--timeout, --force-stop)
+	migrate vm <name>     Migrate a VM to another node (--target, --online)
+	build <config file>   Build a VM template from an HCL or JSON config
+	discover key=value... Print newline-separated addresses of matching nodes/VMs
 `
 
 func usage() {
@@ -50,9 +68,25 @@ func main() {
 	skipTLSVerify := flag.Bool("skiptls", false, "Skip TLS verification. Avoid this whenver possible.")
 	debug := flag.Bool("debug", false, "Debug mode")
 	realm := flag.String("realm", "pam", "Authentication realm") // See https://pve.proxmox.com/wiki/User_Management#pveum_authentication_realms
+	timeout := flag.Duration("timeout", 0, "Abort the command if it hasn't finished after this long (e.g. 30s, 5m). Zero means no deadline.")
+	outputFlag := flag.String("output", "text", "Output format for list commands: text, json or yaml")
 
 	flag.Parse()
 
+	outputFormat, err := output.ParseFormat(*outputFlag)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, *timeout)
+		defer cancel()
+	}
+
 	command := flag.Arg(0)
 
 	if command == "" {
@@ -92,7 +126,7 @@ func main() {
 		usernameWithRealm = usernameWithRealm + "@" + *realm
 	}
 
-	err = client.Login(usernameWithRealm, *password, *otp)
+	err = client.LoginCtx(ctx, usernameWithRealm, *password, *otp)
 	if err != nil {
 		log.Fatalln("Failed to login", err.Error())
 	}
@@ -112,15 +146,89 @@ func main() {
 		case "c", "cluster", "clusters":
 			listClusters(client)
 		case "n", "node", "nodes":
-			listNodes(client)
+			listNodes(ctx, client, outputFormat)
 		case "s", "storage":
-			listStorages(client)
+			listStorages(ctx, client, outputFormat)
 		case "v", "vm", "vms":
-			listVMs(client)
+			listVMs(ctx, client, outputFormat)
+		case "lxc", "lxcs":
+			listLxc(ctx, client, outputFormat)
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "create":
+		switch flag.Arg(1) {
+		case "lxc":
+			createLxc(ctx, client, flag.Args()[2:])
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "destroy":
+		switch flag.Arg(1) {
+		case "lxc":
+			destroyLxc(ctx, client, flag.Arg(2))
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "start":
+		switch flag.Arg(1) {
+		case "lxc":
+			startLxc(ctx, client, flag.Arg(2))
+		case "vm":
+			startVM(ctx, client, flag.Arg(2), flag.Args()[3:])
 		default:
 			help(command)
 			os.Exit(0)
 		}
+	case "stop":
+		switch flag.Arg(1) {
+		case "lxc":
+			stopLxc(ctx, client, flag.Arg(2))
+		case "vm":
+			stopVM(ctx, client, flag.Arg(2))
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "reboot":
+		switch flag.Arg(1) {
+		case "vm":
+			rebootVM(ctx, client, flag.Arg(2), flag.Args()[3:])
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "resume":
+		switch flag.Arg(1) {
+		case "vm":
+			resumeVM(ctx, client, flag.Arg(2), flag.Args()[3:])
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "shutdown":
+		switch flag.Arg(1) {
+		case "vm":
+			shutdownVM(ctx, client, flag.Arg(2), flag.Args()[3:])
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "migrate":
+		switch flag.Arg(1) {
+		case "vm":
+			migrateVM(ctx, client, flag.Arg(2), flag.Args()[3:])
+		default:
+			help(command)
+			os.Exit(0)
+		}
+	case "build":
+		buildTemplate(ctx, client, flag.Arg(1))
+	case "discover":
+		discoverCmd(ctx, client, flag.Args()[1:])
 	}
 
 }
@@ -137,98 +245,436 @@ func listClusters(client *proxmox.Client) {
 	// }
 }
 
-func listNodes(client *proxmox.Client) {
-	nodes, err := client.GetNodeList()
+func listNodes(ctx context.Context, client *proxmox.Client, format output.Format) {
+	nodes, err := client.GetNodeListCtx(ctx)
 	if err != nil {
 		log.Fatalln("Failed to list nodes", err.Error())
 	}
-	for _, nodeInfo := range nodes {
-		for _, node := range nodeInfo.([]interface{}) {
-			name, _ := node.(map[string]interface{})["node"].(string)
-			fmt.Println(name)
-			for nodeAttr, nodeAttrValue := range node.(map[string]interface{}) {
-				if nodeAttr != "node" {
-					fmt.Println("\t", nodeAttr, ":", nodeAttrValue)
-				}
+
+	var summaries []output.NodeSummary
+	for _, node := range nodes {
+		nodeMap := node.(map[string]interface{})
+		name, _ := nodeMap["node"].(string)
+		status, _ := nodeMap["status"].(string)
+		attrs := map[string]interface{}{}
+		for k, v := range nodeMap {
+			if k != "node" {
+				attrs[k] = v
 			}
 		}
+		summaries = append(summaries, output.NodeSummary{Name: name, Status: status, Attrs: attrs})
 	}
+
+	output.Write(os.Stdout, format, summaries, func(w io.Writer, v interface{}) {
+		for _, n := range v.([]output.NodeSummary) {
+			fmt.Fprintln(w, n.Name)
+			for attr, val := range n.Attrs {
+				fmt.Fprintln(w, "\t", attr, ":", val)
+			}
+		}
+	})
 }
 
-func listStorages(client *proxmox.Client) {
-	nodes, err := client.GetNodeList()
+func listStorages(ctx context.Context, client *proxmox.Client, format output.Format) {
+	nodes, err := client.GetNodeListCtx(ctx)
 	if err != nil {
 		log.Fatalln("Failed to list nodes", err.Error())
 	}
-	for _, nodeInfo := range nodes {
-		for _, node := range nodeInfo.([]interface{}) {
-			nodeName := node.(map[string]interface{})["node"].(string)
-			fmt.Println(nodeName)
-			storages, err := client.ListStorages(nodeName)
-			if err != nil {
-				log.Fatalf("Failed to fetch storages for node %s: %s\n", nodeName, err.Error())
-			}
 
-			for _, storage := range storages {
-				storageName := storage.(map[string]interface{})["storage"].(string)
-				fmt.Println("\t", storageName)
-				for attrName, attrVal := range storage.(map[string]interface{}) {
-					if attrName != "storage" {
-						fmt.Printf("\t\t%s:%+v\n", attrName, attrVal)
-					}
+	var summaries []output.StorageSummary
+	for _, node := range nodes {
+		nodeName := node.(map[string]interface{})["node"].(string)
+		storages, err := client.ListStoragesCtx(ctx, nodeName)
+		if err != nil {
+			log.Fatalf("Failed to fetch storages for node %s: %s\n", nodeName, err.Error())
+		}
+
+		for _, storage := range storages {
+			storageMap := storage.(map[string]interface{})
+			storageName := storageMap["storage"].(string)
+			attrs := map[string]interface{}{}
+			for k, v := range storageMap {
+				if k != "storage" {
+					attrs[k] = v
 				}
 			}
+			summaries = append(summaries, output.StorageSummary{Node: nodeName, Name: storageName, Attrs: attrs})
 		}
 	}
 
-	// TODO: Make API available
-	// clusters, err := client.GetClusterList()
-	// if err != nil {
-	// 	log.Fatalln("Failed to list clusters", err.Error())
-	// }
-	// for _, cluster := range clusters {
-	// 	fmt.Println(cluster)
-	// }
+	output.Write(os.Stdout, format, summaries, func(w io.Writer, v interface{}) {
+		lastNode := ""
+		for _, s := range v.([]output.StorageSummary) {
+			if s.Node != lastNode {
+				fmt.Fprintln(w, s.Node)
+				lastNode = s.Node
+			}
+			fmt.Fprintln(w, "\t", s.Name)
+			for attrName, attrVal := range s.Attrs {
+				fmt.Fprintf(w, "\t\t%s:%+v\n", attrName, attrVal)
+			}
+		}
+	})
 }
 
-func listVMs(client *proxmox.Client) {
-	vms, err := client.GetVmList()
+func listVMs(ctx context.Context, client *proxmox.Client, format output.Format) {
+	vms, err := client.GetVmListCtx(ctx)
 	if err != nil {
 		log.Fatalln("Failed to list VMs", err.Error())
 	}
-	for _, vmInfo := range vms {
-		for _, vm := range vmInfo.([]interface{}) {
-			name, _ := vm.(map[string]interface{})["name"].(string)
-			fmt.Println(name)
-			fmt.Println(" Status:")
-			for vmAttr, vmAttrVal := range vm.(map[string]interface{}) {
-				if vmAttr != "name" {
-					fmt.Printf("\t%s: %+v\n", vmAttr, vmAttrVal)
-				}
+
+	var summaries []output.VMSummary
+	for _, vm := range vms {
+		vmMap := vm.(map[string]interface{})
+		name, _ := vmMap["name"].(string)
+		status := map[string]interface{}{}
+		for k, v := range vmMap {
+			if k != "name" {
+				status[k] = v
 			}
+		}
+
+		vmRef, err := client.GetVmRefByNameCtx(ctx, name)
+		if err != nil {
+			log.Fatalln("Failed to get VM reference", err.Error())
+		}
+		vmConfig, err := client.GetVmConfigCtx(ctx, vmRef)
+		if err != nil {
+			log.Fatalln("Failed to get VM config", err.Error())
+		}
+
+		var agentNetworkInterfaces []interface{}
+		if ifaces, err := client.GetVmAgentNetworkInterfacesCtx(ctx, vmRef); err == nil {
+			agentNetworkInterfaces = ifaces
+		}
 
-			vmRef, err := client.GetVmRefByName(name)
-			if err != nil {
-				log.Fatalln("Failed to get VM reference", err.Error())
+		summaries = append(summaries, output.VMSummary{
+			Name:                   name,
+			Status:                 status,
+			Config:                 vmConfig,
+			AgentNetworkInterfaces: agentNetworkInterfaces,
+		})
+	}
+
+	output.Write(os.Stdout, format, summaries, func(w io.Writer, v interface{}) {
+		for _, vm := range v.([]output.VMSummary) {
+			fmt.Fprintln(w, vm.Name)
+			fmt.Fprintln(w, " Status:")
+			for attr, val := range vm.Status {
+				fmt.Fprintf(w, "\t%s: %+v\n", attr, val)
+			}
+			fmt.Fprintln(w, " Config:")
+			for attr, val := range vm.Config {
+				fmt.Fprintf(w, "\t%s: %+v\n", attr, val)
 			}
-			vmConfig, err := client.GetVmConfig(vmRef)
-			if err != nil {
-				log.Fatalln("Failed to get VM config", err.Error())
+			fmt.Fprintln(w, " Agent network interfaces:")
+			if len(vm.AgentNetworkInterfaces) == 0 {
+				fmt.Fprintln(w, "\tNot available")
+				continue
 			}
-			fmt.Println(" Config:")
-			for vmConfigAttr, vmConfigAttrValue := range vmConfig {
-				fmt.Printf("\t%s: %+v\n", vmConfigAttr, vmConfigAttrValue)
+			for _, iface := range vm.AgentNetworkInterfaces {
+				fmt.Fprintln(w, "\t", iface)
 			}
+		}
+	})
+}
 
-			fmt.Println(" Agent network interfaces:")
-			agentNetworkInterfaces, err := client.GetVmAgentNetworkInterfaces(vmRef)
-			if err != nil {
-				fmt.Println("\tNot available:", err.Error())
-			} else {
-				for _, agentNetworkInterface := range agentNetworkInterfaces {
-					fmt.Println("\t", agentNetworkInterface)
-				}
+func listLxc(ctx context.Context, client *proxmox.Client, format output.Format) {
+	containers, err := client.GetLxcListCtx(ctx)
+	if err != nil {
+		log.Fatalln("Failed to list LXC containers", err.Error())
+	}
+
+	var summaries []output.VMSummary
+	for _, container := range containers {
+		containerMap, ok := container.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _ := containerMap["name"].(string)
+		status := map[string]interface{}{}
+		for k, v := range containerMap {
+			if k != "name" {
+				status[k] = v
 			}
 		}
+		summaries = append(summaries, output.VMSummary{Name: name, Status: status})
+	}
+
+	output.Write(os.Stdout, format, summaries, func(w io.Writer, v interface{}) {
+		for _, c := range v.([]output.VMSummary) {
+			fmt.Fprintln(w, c.Name)
+			for attr, val := range c.Status {
+				fmt.Fprintln(w, "\t", attr, ":", val)
+			}
+		}
+	})
+}
+
+func createLxc(ctx context.Context, client *proxmox.Client, args []string) {
+	fs := flag.NewFlagSet("create lxc", flag.ExitOnError)
+	hostname := fs.String("hostname", "", "Container hostname")
+	osTemplate := fs.String("ostemplate", "", "Volume identifier of the OS template, e.g. local:vztmpl/debian-12.tar.zst")
+	storage := fs.String("storage", "local-lvm", "Storage to place the root filesystem on")
+	targetNode := fs.String("target-node", "", "Node to create the container on")
+	net := fs.String("net", "name=eth0,bridge=vmbr0,ip=dhcp", "Network config for eth0, e.g. name=eth0,bridge=vmbr0,ip=dhcp")
+	password := fs.String("password", "", "Root password")
+	pool := fs.String("pool", "", "Resource pool to add the container to")
+	cores := fs.Int("cores", 0, "Number of CPU cores")
+	memory := fs.Int("memory", 0, "Memory in MB")
+	swap := fs.Int("swap", 0, "Swap in MB")
+	unprivileged := fs.Bool("unprivileged", true, "Create an unprivileged container")
+	nesting := fs.Bool("nesting", false, "Allow nesting (run Docker/LXC inside the container)")
+	vmid := fs.Int("vmid", 0, "VMID to assign; 0 picks the next free one from the cluster")
+	fs.Parse(args)
+
+	if *targetNode == "" || *hostname == "" || *osTemplate == "" {
+		log.Fatalln("--target-node, --hostname and --ostemplate are required")
+	}
+
+	id := *vmid
+	if id == 0 {
+		next, err := client.NextVmIdCtx(ctx)
+		if err != nil {
+			log.Fatalln("Failed to get next free vmid", err.Error())
+		}
+		id = next
+	}
+
+	opts := proxmox.LxcCreateOptions{
+		Hostname:     *hostname,
+		OsTemplate:   *osTemplate,
+		Storage:      *storage,
+		Password:     *password,
+		Pool:         *pool,
+		Cores:        *cores,
+		Memory:       *memory,
+		Swap:         *swap,
+		Unprivileged: *unprivileged,
+		Nesting:      *nesting,
+		Networks:     []proxmox.LxcNetworkConfig{parseLxcNetworkConfig(*net)},
+	}
+
+	if err := client.CreateLxcCtx(ctx, *targetNode, id, opts); err != nil {
+		log.Fatalln("Failed to create LXC container", err.Error())
+	}
+	fmt.Println("Created", *hostname, "on", *targetNode)
+}
+
+// parseLxcNetworkConfig parses a Proxmox net[n] string such as
+// "name=eth0,bridge=vmbr0,ip=dhcp" into an LxcNetworkConfig.
+func parseLxcNetworkConfig(s string) proxmox.LxcNetworkConfig {
+	cfg := proxmox.LxcNetworkConfig{}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "name":
+			cfg.Name = kv[1]
+		case "bridge":
+			cfg.Bridge = kv[1]
+		case "ip":
+			cfg.IP = kv[1]
+		}
+	}
+	return cfg
+}
+
+func destroyLxc(ctx context.Context, client *proxmox.Client, name string) {
+	vmr := mustResolveVmRef(ctx, client, name)
+	if err := client.DeleteLxcCtx(ctx, vmr); err != nil {
+		log.Fatalln("Failed to destroy LXC container", err.Error())
+	}
+	fmt.Println("Destroyed", name)
+}
+
+func startLxc(ctx context.Context, client *proxmox.Client, name string) {
+	vmr := mustResolveVmRef(ctx, client, name)
+	if err := client.StartLxcCtx(ctx, vmr); err != nil {
+		log.Fatalln("Failed to start LXC container", err.Error())
+	}
+	fmt.Println("Started", name)
+}
+
+func stopLxc(ctx context.Context, client *proxmox.Client, name string) {
+	vmr := mustResolveVmRef(ctx, client, name)
+	if err := client.StopLxcCtx(ctx, vmr); err != nil {
+		log.Fatalln("Failed to stop LXC container", err.Error())
+	}
+	fmt.Println("Stopped", name)
+}
+
+func startVM(ctx context.Context, client *proxmox.Client, name string, args []string) {
+	fs := flag.NewFlagSet("start vm", flag.ExitOnError)
+	machine := fs.String("machine", "", "Override the guest's machine type, e.g. q35")
+	forceCPU := fs.String("force-cpu", "", "Override the guest's CPU model")
+	migratedFrom := fs.String("migrated-from", "", "Source node of an incoming migration")
+	migrationNetwork := fs.String("migration-network", "", "CIDR to send migration traffic over")
+	migrationType := fs.String("migration-type", "", "Migration transport: secure or insecure")
+	stateURI := fs.String("state-uri", "", "Resume from this suspended-state URI")
+	targetStorage := fs.String("target-storage", "", "Storage mapping to rewrite onto for a live migration")
+	skipLock := fs.Bool("skip-lock", false, "Skip acquiring the guest lock")
+	timeout := fs.Int("timeout", 0, "Seconds to wait for the start task to complete")
+	fs.Parse(args)
+
+	vmr := mustResolveVmRef(ctx, client, name)
+	opts := proxmox.VMStartOptions{
+		ForceCPU:         *forceCPU,
+		Machine:          *machine,
+		MigratedFrom:     *migratedFrom,
+		MigrationNetwork: *migrationNetwork,
+		MigrationType:    *migrationType,
+		SkipLock:         *skipLock,
+		StateURI:         *stateURI,
+		TargetStorage:    *targetStorage,
+		Timeout:          *timeout,
+	}
+	if err := client.StartVmCtx(ctx, vmr, opts); err != nil {
+		log.Fatalln("Failed to start VM", err.Error())
+	}
+	fmt.Println("Started", name)
+}
+
+func stopVM(ctx context.Context, client *proxmox.Client, name string) {
+	vmr := mustResolveVmRef(ctx, client, name)
+	if err := client.StopVmCtx(ctx, vmr); err != nil {
+		log.Fatalln("Failed to stop VM", err.Error())
+	}
+	fmt.Println("Stopped", name)
+}
+
+func rebootVM(ctx context.Context, client *proxmox.Client, name string, args []string) {
+	fs := flag.NewFlagSet("reboot vm", flag.ExitOnError)
+	timeout := fs.Int("timeout", 0, "Seconds to wait for the guest to shut down before giving up")
+	fs.Parse(args)
+
+	vmr := mustResolveVmRef(ctx, client, name)
+	if err := client.RebootVmCtx(ctx, vmr, proxmox.VMRebootOptions{Timeout: *timeout}); err != nil {
+		log.Fatalln("Failed to reboot VM", err.Error())
+	}
+	fmt.Println("Rebooted", name)
+}
+
+func resumeVM(ctx context.Context, client *proxmox.Client, name string, args []string) {
+	fs := flag.NewFlagSet("resume vm", flag.ExitOnError)
+	noCheck := fs.Bool("no-check", false, "Skip sanity checks before resuming")
+	skipLock := fs.Bool("skip-lock", false, "Skip acquiring the guest lock")
+	fs.Parse(args)
+
+	vmr := mustResolveVmRef(ctx, client, name)
+	if err := client.ResumeVmCtx(ctx, vmr, proxmox.VMResumeOptions{NoCheck: *noCheck, SkipLock: *skipLock}); err != nil {
+		log.Fatalln("Failed to resume VM", err.Error())
+	}
+	fmt.Println("Resumed", name)
+}
+
+func shutdownVM(ctx context.Context, client *proxmox.Client, name string, args []string) {
+	fs := flag.NewFlagSet("shutdown vm", flag.ExitOnError)
+	timeout := fs.Int("timeout", 0, "Seconds to wait for the guest to shut down before giving up")
+	forceStop := fs.Bool("force-stop", false, "Force a hard stop once the timeout elapses")
+	skipLock := fs.Bool("skip-lock", false, "Skip acquiring the guest lock")
+	fs.Parse(args)
+
+	vmr := mustResolveVmRef(ctx, client, name)
+	opts := proxmox.VMShutdownOptions{Timeout: *timeout, ForceStop: *forceStop, SkipLock: *skipLock}
+	if err := client.ShutdownVmCtx(ctx, vmr, opts); err != nil {
+		log.Fatalln("Failed to shut down VM", err.Error())
+	}
+	fmt.Println("Shut down", name)
+}
+
+func migrateVM(ctx context.Context, client *proxmox.Client, name string, args []string) {
+	fs := flag.NewFlagSet("migrate vm", flag.ExitOnError)
+	target := fs.String("target", "", "Destination node (required)")
+	online := fs.Bool("online", false, "Live-migrate a running VM")
+	withLocalDisks := fs.Bool("with-local-disks", false, "Also migrate disks that aren't on shared storage")
+	targetStorage := fs.String("target-storage", "", "Storage mapping to rewrite onto on the destination node")
+	migrationNetwork := fs.String("migration-network", "", "CIDR to send migration traffic over")
+	migrationType := fs.String("migration-type", "", "Migration transport: secure or insecure")
+	fs.Parse(args)
+
+	if *target == "" {
+		log.Fatalln("--target is required")
+	}
+
+	vmr := mustResolveVmRef(ctx, client, name)
+	opts := proxmox.VMMigrateOptions{
+		Target:           *target,
+		Online:           *online,
+		WithLocalDisks:   *withLocalDisks,
+		TargetStorage:    *targetStorage,
+		MigrationNetwork: *migrationNetwork,
+		MigrationType:    *migrationType,
+	}
+	if err := client.MigrateVmCtx(ctx, vmr, opts); err != nil {
+		log.Fatalln("Failed to migrate VM", err.Error())
+	}
+	fmt.Println("Migrating", name, "to", *target)
+}
+
+// discoverCmd implements `discover kind=vm tag=consul-server addr_type=private_v4`,
+// printing one matching address per line so it can be wired into
+// consul/nomad cloud auto-join as an external provider.
+func discoverCmd(ctx context.Context, client *proxmox.Client, args []string) {
+	filter := proxmox.DiscoverFilter{AddrType: proxmox.PrivateV4}
+
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 {
+			log.Fatalf("invalid discover argument %q, want key=value\n", arg)
+		}
+		switch kv[0] {
+		case "kind":
+			filter.Kind = kv[1]
+		case "tag":
+			filter.Tag = kv[1]
+		case "addr_type", "addr-type":
+			filter.AddrType = proxmox.AddrType(kv[1])
+		default:
+			log.Fatalf("unknown discover key %q\n", kv[0])
+		}
+	}
+
+	if filter.Kind == "" {
+		log.Fatalln("discover requires kind=vm or kind=node")
+	}
+
+	addrs, err := proxmox.NewDiscoverer(client).Discover(ctx, filter)
+	if err != nil {
+		log.Fatalln("Discover failed", err.Error())
+	}
+	for _, addr := range addrs {
+		fmt.Println(addr)
+	}
+}
+
+func buildTemplate(ctx context.Context, client *proxmox.Client, configPath string) {
+	if configPath == "" {
+		log.Fatalln("Usage: build <config file>")
+	}
+
+	cfg, err := builder.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalln("Failed to load build config", err.Error())
+	}
+
+	templateVMID, err := builder.Build(ctx, client, cfg)
+	if err != nil {
+		log.Fatalln("Build failed", err.Error())
+	}
+
+	fmt.Println("Template ready:", templateVMID)
+}
+
+func mustResolveVmRef(ctx context.Context, client *proxmox.Client, name string) *proxmox.VmRef {
+	if name == "" {
+		log.Fatalln("Guest name is required")
+	}
+	vmr, err := client.GetVmRefByNameCtx(ctx, name)
+	if err != nil {
+		log.Fatalln("Failed to get guest reference", err.Error())
 	}
+	return vmr
 }
@@ -0,0 +1,294 @@
+// Package proxmox provides a thin client for the Proxmox VE REST API.
+package proxmox
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client is a session-authenticated handle to a Proxmox VE API server.
+type Client struct {
+	httpClient  *http.Client
+	ApiUrl      string
+	TaskTimeout int
+
+	authTicket string
+	csrfToken  string
+}
+
+// NewClient builds a Client for the given Proxmox API URL. If hclient is
+// nil, a new http.Client is used. tlsConf, when non-nil, overrides the
+// client's transport TLS configuration.
+func NewClient(apiUrl string, hclient *http.Client, tlsConf *tls.Config, taskTimeout int) (*Client, error) {
+	if hclient == nil {
+		hclient = &http.Client{}
+	}
+	if tlsConf != nil {
+		hclient.Transport = &http.Transport{TLSClientConfig: tlsConf}
+	}
+	return &Client{
+		httpClient:  hclient,
+		ApiUrl:      strings.TrimRight(apiUrl, "/"),
+		TaskTimeout: taskTimeout,
+	}, nil
+}
+
+// Login authenticates against the Proxmox API and stores the resulting
+// session ticket and CSRF prevention token on the client.
+func (c *Client) Login(username, password, otp string) error {
+	return c.LoginCtx(context.Background(), username, password, otp)
+}
+
+// LoginCtx is the context-aware variant of Login.
+func (c *Client) LoginCtx(ctx context.Context, username, password, otp string) error {
+	form := url.Values{
+		"username": {username},
+		"password": {password},
+	}
+	if otp != "" {
+		form.Set("otp", otp)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.ApiUrl+"/access/ticket", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("login failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Ticket              string `json:"ticket"`
+			CSRFPreventionToken string `json:"CSRFPreventionToken"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("decoding login response: %w", err)
+	}
+
+	c.authTicket = body.Data.Ticket
+	c.csrfToken = body.Data.CSRFPreventionToken
+	return nil
+}
+
+// GetNodeList returns the raw cluster node list.
+func (c *Client) GetNodeList() ([]interface{}, error) {
+	return c.GetNodeListCtx(context.Background())
+}
+
+// GetNodeListCtx is the context-aware variant of GetNodeList.
+func (c *Client) GetNodeListCtx(ctx context.Context) ([]interface{}, error) {
+	var data []interface{}
+	err := c.getCtx(ctx, "/nodes", &data)
+	return data, err
+}
+
+// GetNodeNetwork returns the raw list of network interfaces configured on
+// a node.
+func (c *Client) GetNodeNetwork(node string) ([]interface{}, error) {
+	return c.GetNodeNetworkCtx(context.Background(), node)
+}
+
+// GetNodeNetworkCtx is the context-aware variant of GetNodeNetwork.
+func (c *Client) GetNodeNetworkCtx(ctx context.Context, node string) ([]interface{}, error) {
+	var data []interface{}
+	err := c.getCtx(ctx, fmt.Sprintf("/nodes/%s/network", node), &data)
+	return data, err
+}
+
+// NextVmId asks the cluster for the next free VMID.
+func (c *Client) NextVmId() (int, error) {
+	return c.NextVmIdCtx(context.Background())
+}
+
+// NextVmIdCtx is the context-aware variant of NextVmId.
+func (c *Client) NextVmIdCtx(ctx context.Context) (int, error) {
+	var data string
+	if err := c.getCtx(ctx, "/cluster/nextid", &data); err != nil {
+		return 0, err
+	}
+	id, err := strconv.Atoi(data)
+	if err != nil {
+		return 0, fmt.Errorf("parsing next vmid %q: %w", data, err)
+	}
+	return id, nil
+}
+
+// ListStorages returns the raw storage list for a given node.
+func (c *Client) ListStorages(node string) ([]interface{}, error) {
+	return c.ListStoragesCtx(context.Background(), node)
+}
+
+// ListStoragesCtx is the context-aware variant of ListStorages.
+func (c *Client) ListStoragesCtx(ctx context.Context, node string) ([]interface{}, error) {
+	var data []interface{}
+	err := c.getCtx(ctx, fmt.Sprintf("/nodes/%s/storage", node), &data)
+	return data, err
+}
+
+// GetVmList returns the raw list of QEMU/LXC guests across the cluster.
+func (c *Client) GetVmList() ([]interface{}, error) {
+	return c.GetVmListCtx(context.Background())
+}
+
+// GetVmListCtx is the context-aware variant of GetVmList.
+func (c *Client) GetVmListCtx(ctx context.Context) ([]interface{}, error) {
+	var data []interface{}
+	err := c.getCtx(ctx, "/cluster/resources?type=vm", &data)
+	return data, err
+}
+
+// VmRef identifies a single guest and the node it currently lives on.
+type VmRef struct {
+	VmId int
+	node string
+	vmType string
+}
+
+// GetVmRefByName resolves a guest name to a VmRef by scanning the cluster
+// resource list.
+func (c *Client) GetVmRefByName(name string) (*VmRef, error) {
+	return c.GetVmRefByNameCtx(context.Background(), name)
+}
+
+// GetVmRefByNameCtx is the context-aware variant of GetVmRefByName.
+func (c *Client) GetVmRefByNameCtx(ctx context.Context, name string) (*VmRef, error) {
+	vms, err := c.GetVmListCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, vm := range vms {
+		vmMap, ok := vm.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if vmMap["name"] != name {
+			continue
+		}
+		vmid, _ := vmMap["vmid"].(float64)
+		node, _ := vmMap["node"].(string)
+		vmType, _ := vmMap["type"].(string)
+		return &VmRef{VmId: int(vmid), node: node, vmType: vmType}, nil
+	}
+	return nil, fmt.Errorf("vm %q not found", name)
+}
+
+// GetVmConfig returns the raw guest configuration for the given VmRef.
+func (c *Client) GetVmConfig(vmr *VmRef) (map[string]interface{}, error) {
+	return c.GetVmConfigCtx(context.Background(), vmr)
+}
+
+// GetVmConfigCtx is the context-aware variant of GetVmConfig.
+func (c *Client) GetVmConfigCtx(ctx context.Context, vmr *VmRef) (map[string]interface{}, error) {
+	var data map[string]interface{}
+	err := c.getCtx(ctx, fmt.Sprintf("/nodes/%s/%s/%d/config", vmr.node, vmr.vmType, vmr.VmId), &data)
+	return data, err
+}
+
+// GetVmAgentNetworkInterfaces returns the guest-agent-reported network
+// interfaces for a running VM. It requires qemu-guest-agent to be installed
+// and reachable.
+func (c *Client) GetVmAgentNetworkInterfaces(vmr *VmRef) ([]interface{}, error) {
+	return c.GetVmAgentNetworkInterfacesCtx(context.Background(), vmr)
+}
+
+// GetVmAgentNetworkInterfacesCtx is the context-aware variant of
+// GetVmAgentNetworkInterfaces.
+func (c *Client) GetVmAgentNetworkInterfacesCtx(ctx context.Context, vmr *VmRef) ([]interface{}, error) {
+	var data struct {
+		Result []interface{} `json:"result"`
+	}
+	err := c.getCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/agent/network-get-interfaces", vmr.node, vmr.VmId), &data)
+	return data.Result, err
+}
+
+// getCtx performs an authenticated GET against the API and decodes the
+// "data" field of the response into out.
+func (c *Client) getCtx(ctx context.Context, path string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.ApiUrl+path, nil)
+	if err != nil {
+		return err
+	}
+	c.setAuthHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed: %s", path, resp.Status)
+	}
+
+	envelope := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	return json.NewDecoder(resp.Body).Decode(&envelope)
+}
+
+// postFormCtx submits a urlencoded form against the API and discards the
+// response body beyond checking for success. Proxmox task-queuing endpoints
+// (create/start/stop/...) respond with a task UPID here, which callers that
+// need to await completion can poll for separately.
+func (c *Client) postFormCtx(ctx context.Context, path string, form url.Values) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.ApiUrl+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c.setAuthHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed: %s", path, resp.Status)
+	}
+	return nil
+}
+
+// deleteCtx issues an authenticated DELETE against the API.
+func (c *Client) deleteCtx(ctx context.Context, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.ApiUrl+path, nil)
+	if err != nil {
+		return err
+	}
+	c.setAuthHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request to %s failed: %s", path, resp.Status)
+	}
+	return nil
+}
+
+func (c *Client) setAuthHeaders(req *http.Request) {
+	req.AddCookie(&http.Cookie{Name: "PVEAuthCookie", Value: c.authTicket})
+	if req.Method != http.MethodGet {
+		req.Header.Set("CSRFPreventionToken", c.csrfToken)
+	}
+}
@@ -0,0 +1,183 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// AddrType selects which family of address Discover should return.
+type AddrType string
+
+const (
+	PrivateV4 AddrType = "private_v4"
+	PublicV4  AddrType = "public_v4"
+	PrivateV6 AddrType = "private_v6"
+	PublicV6  AddrType = "public_v6"
+)
+
+// DiscoverFilter narrows Discover to a kind of resource, an optional tag,
+// and the address family to report.
+type DiscoverFilter struct {
+	Kind     string // "vm" or "node"; "lxc" is rejected by Discover
+	Tag      string // only match resources carrying this tag; empty matches all
+	AddrType AddrType
+}
+
+// Discoverer resolves cluster members to addresses for use as a
+// service-discovery provider (e.g. consul/nomad cloud auto-join).
+type Discoverer struct {
+	Client *Client
+}
+
+// NewDiscoverer returns a Discoverer backed by client.
+func NewDiscoverer(client *Client) *Discoverer {
+	return &Discoverer{Client: client}
+}
+
+// Discover returns the addresses of every cluster member matching filter,
+// newline-friendly (one address per slice entry, no surrounding
+// whitespace), skipping members an address of the requested family
+// couldn't be found for.
+func (d *Discoverer) Discover(ctx context.Context, filter DiscoverFilter) ([]string, error) {
+	switch filter.Kind {
+	case "node":
+		return d.discoverNodes(ctx, filter)
+	case "vm":
+		return d.discoverGuests(ctx, filter)
+	case "lxc":
+		// LXC containers have no qemu-guest-agent equivalent in this client,
+		// so there is no way to resolve a container's guest IP. Fail loudly
+		// rather than silently returning an empty address list.
+		return nil, fmt.Errorf("discover: kind=lxc is not supported (no guest-agent network discovery for containers); use kind=vm or kind=node")
+	default:
+		return nil, fmt.Errorf("unknown discover kind %q (want node or vm)", filter.Kind)
+	}
+}
+
+func (d *Discoverer) discoverNodes(ctx context.Context, filter DiscoverFilter) ([]string, error) {
+	nodes, err := d.Client.GetNodeListCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, node := range nodes {
+		nodeMap, ok := node.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if !hasTag(nodeMap, filter.Tag) {
+			continue
+		}
+		name, _ := nodeMap["node"].(string)
+
+		ifaces, err := d.Client.GetNodeNetworkCtx(ctx, name)
+		if err != nil {
+			continue
+		}
+		if addr, ok := firstMatchingAddr(ifaces, filter.AddrType); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+func (d *Discoverer) discoverGuests(ctx context.Context, filter DiscoverFilter) ([]string, error) {
+	resources, err := d.Client.GetVmListCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var addrs []string
+	for _, resource := range resources {
+		vmMap, ok := resource.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if !hasTag(vmMap, filter.Tag) {
+			continue
+		}
+		name, _ := vmMap["name"].(string)
+
+		vmr, err := d.Client.GetVmRefByNameCtx(ctx, name)
+		if err != nil {
+			continue
+		}
+		ifaces, err := d.Client.GetVmAgentNetworkInterfacesCtx(ctx, vmr)
+		if err != nil {
+			continue
+		}
+		if addr, ok := firstMatchingAddr(ifaces, filter.AddrType); ok {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs, nil
+}
+
+// hasTag reports whether resource carries tag in its semicolon-separated
+// "tags" field. An empty tag always matches.
+func hasTag(resource map[string]interface{}, tag string) bool {
+	if tag == "" {
+		return true
+	}
+	tags, _ := resource["tags"].(string)
+	for _, t := range strings.Split(tags, ";") {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// firstMatchingAddr walks guest-agent ("ip-addresses": [{"ip-address": ...}])
+// or node network ("address": ...) interface entries looking for the first
+// address of the requested family, skipping loopback and link-local
+// addresses.
+func firstMatchingAddr(ifaces []interface{}, addrType AddrType) (string, bool) {
+	for _, iface := range ifaces {
+		ifaceMap, ok := iface.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if addr, ok := ifaceMap["address"].(string); ok && matchesAddrType(addr, addrType) {
+			return addr, true
+		}
+
+		ipAddresses, _ := ifaceMap["ip-addresses"].([]interface{})
+		for _, ipEntry := range ipAddresses {
+			ipMap, ok := ipEntry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			ipStr, _ := ipMap["ip-address"].(string)
+			if matchesAddrType(ipStr, addrType) {
+				return ipStr, true
+			}
+		}
+	}
+	return "", false
+}
+
+func matchesAddrType(s string, addrType AddrType) bool {
+	ip := net.ParseIP(s)
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() {
+		return false
+	}
+
+	isV4 := ip.To4() != nil
+	switch addrType {
+	case PrivateV4:
+		return isV4 && ip.IsPrivate()
+	case PublicV4:
+		return isV4 && !ip.IsPrivate()
+	case PrivateV6:
+		return !isV4 && ip.IsPrivate()
+	case PublicV6:
+		return !isV4 && !ip.IsPrivate()
+	default:
+		return false
+	}
+}
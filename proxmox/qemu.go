@@ -0,0 +1,101 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// QemuCreateOptions captures the subset of `/nodes/{node}/qemu` create
+// parameters needed to provision a VM for templating.
+type QemuCreateOptions struct {
+	Name    string
+	Ide2    string // cdrom mapping, e.g. "local:iso/debian.iso,media=cdrom"
+	Scsi0   string // primary disk, e.g. "local-lvm:32"
+	Net0    string // e.g. "virtio,bridge=vmbr0"
+	Cores   int
+	Memory  int
+	OsType  string
+}
+
+func (o QemuCreateOptions) values(vmid int) url.Values {
+	v := url.Values{"vmid": {strconv.Itoa(vmid)}}
+	if o.Name != "" {
+		v.Set("name", o.Name)
+	}
+	if o.Ide2 != "" {
+		v.Set("ide2", o.Ide2)
+	}
+	if o.Scsi0 != "" {
+		v.Set("scsi0", o.Scsi0)
+	}
+	if o.Net0 != "" {
+		v.Set("net0", o.Net0)
+	}
+	if o.Cores > 0 {
+		v.Set("cores", strconv.Itoa(o.Cores))
+	}
+	if o.Memory > 0 {
+		v.Set("memory", strconv.Itoa(o.Memory))
+	}
+	if o.OsType != "" {
+		v.Set("ostype", o.OsType)
+	}
+	return v
+}
+
+// CreateQemu creates a new QEMU VM on targetNode with the given vmid and
+// options.
+func (c *Client) CreateQemu(targetNode string, vmid int, opts QemuCreateOptions) error {
+	return c.CreateQemuCtx(context.Background(), targetNode, vmid, opts)
+}
+
+// CreateQemuCtx is the context-aware variant of CreateQemu.
+func (c *Client) CreateQemuCtx(ctx context.Context, targetNode string, vmid int, opts QemuCreateOptions) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu", targetNode), opts.values(vmid))
+}
+
+// StartVm starts the guest identified by vmr, applying opts (machine type
+// override, migration parameters, ...) as query parameters on the start
+// request.
+func (c *Client) StartVm(vmr *VmRef, opts VMStartOptions) error {
+	return c.StartVmCtx(context.Background(), vmr, opts)
+}
+
+// StartVmCtx is the context-aware variant of StartVm.
+func (c *Client) StartVmCtx(ctx context.Context, vmr *VmRef, opts VMStartOptions) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/status/start", vmr.node, vmr.VmId), opts.values())
+}
+
+// StopVm forcefully powers off the guest identified by vmr.
+func (c *Client) StopVm(vmr *VmRef) error {
+	return c.StopVmCtx(context.Background(), vmr)
+}
+
+// StopVmCtx is the context-aware variant of StopVm.
+func (c *Client) StopVmCtx(ctx context.Context, vmr *VmRef) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/status/stop", vmr.node, vmr.VmId), nil)
+}
+
+// MonitorCmd sends a raw QEMU monitor command to the guest, e.g.
+// "sendkey ret" to inject an Enter keypress.
+func (c *Client) MonitorCmd(vmr *VmRef, command string) error {
+	return c.MonitorCmdCtx(context.Background(), vmr, command)
+}
+
+// MonitorCmdCtx is the context-aware variant of MonitorCmd.
+func (c *Client) MonitorCmdCtx(ctx context.Context, vmr *VmRef, command string) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/monitor", vmr.node, vmr.VmId), url.Values{"command": {command}})
+}
+
+// ConvertToTemplate converts the (stopped) guest identified by vmr into a
+// template, after which it can be cloned but no longer started directly.
+func (c *Client) ConvertToTemplate(vmr *VmRef) error {
+	return c.ConvertToTemplateCtx(context.Background(), vmr)
+}
+
+// ConvertToTemplateCtx is the context-aware variant of ConvertToTemplate.
+func (c *Client) ConvertToTemplateCtx(ctx context.Context, vmr *VmRef) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/template", vmr.node, vmr.VmId), nil)
+}
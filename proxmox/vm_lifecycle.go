@@ -0,0 +1,177 @@
+package proxmox
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// VMStartOptions mirrors the query parameters accepted by
+// /nodes/{node}/qemu/{vmid}/status/start.
+type VMStartOptions struct {
+	ForceCPU         string // overrides the configured cpu model
+	Machine          string // machine type, e.g. "q35"
+	MigratedFrom     string // source node of an incoming migration
+	MigrationNetwork string // CIDR to use for the migration traffic
+	MigrationType    string // "secure" or "insecure"
+	SkipLock         bool
+	StateURI         string // some command to resume a suspended VM
+	TargetStorage    string // mapping to rewrite storage on live migration
+	Timeout          int    // seconds to wait for the task to complete
+}
+
+func (o VMStartOptions) values() url.Values {
+	v := url.Values{}
+	if o.ForceCPU != "" {
+		v.Set("force-cpu", o.ForceCPU)
+	}
+	if o.Machine != "" {
+		v.Set("machine", o.Machine)
+	}
+	if o.MigratedFrom != "" {
+		v.Set("migratedfrom", o.MigratedFrom)
+	}
+	if o.MigrationNetwork != "" {
+		v.Set("migration_network", o.MigrationNetwork)
+	}
+	if o.MigrationType != "" {
+		v.Set("migration_type", o.MigrationType)
+	}
+	if o.SkipLock {
+		v.Set("skiplock", "1")
+	}
+	if o.StateURI != "" {
+		v.Set("stateuri", o.StateURI)
+	}
+	if o.TargetStorage != "" {
+		v.Set("targetstorage", o.TargetStorage)
+	}
+	if o.Timeout > 0 {
+		v.Set("timeout", strconv.Itoa(o.Timeout))
+	}
+	return v
+}
+
+// VMRebootOptions mirrors /nodes/{node}/qemu/{vmid}/status/reboot.
+type VMRebootOptions struct {
+	Timeout int // seconds to wait for the guest to shut down before giving up
+}
+
+func (o VMRebootOptions) values() url.Values {
+	v := url.Values{}
+	if o.Timeout > 0 {
+		v.Set("timeout", strconv.Itoa(o.Timeout))
+	}
+	return v
+}
+
+// VMResumeOptions mirrors /nodes/{node}/qemu/{vmid}/status/resume.
+type VMResumeOptions struct {
+	NoCheck  bool
+	SkipLock bool
+}
+
+func (o VMResumeOptions) values() url.Values {
+	v := url.Values{}
+	if o.NoCheck {
+		v.Set("nocheck", "1")
+	}
+	if o.SkipLock {
+		v.Set("skiplock", "1")
+	}
+	return v
+}
+
+// VMShutdownOptions mirrors /nodes/{node}/qemu/{vmid}/status/shutdown.
+type VMShutdownOptions struct {
+	Timeout   int // seconds to wait for the guest to shut down before giving up
+	ForceStop bool
+	SkipLock  bool
+}
+
+func (o VMShutdownOptions) values() url.Values {
+	v := url.Values{}
+	if o.Timeout > 0 {
+		v.Set("timeout", strconv.Itoa(o.Timeout))
+	}
+	if o.ForceStop {
+		v.Set("forceStop", "1")
+	}
+	if o.SkipLock {
+		v.Set("skiplock", "1")
+	}
+	return v
+}
+
+// VMMigrateOptions mirrors /nodes/{node}/qemu/{vmid}/migrate.
+type VMMigrateOptions struct {
+	Target           string // destination node, required
+	Online           bool   // live-migrate a running VM
+	WithLocalDisks   bool   // migrate disks that aren't on shared storage
+	TargetStorage    string
+	MigrationNetwork string
+	MigrationType    string
+}
+
+func (o VMMigrateOptions) values() url.Values {
+	v := url.Values{"target": {o.Target}}
+	if o.Online {
+		v.Set("online", "1")
+	}
+	if o.WithLocalDisks {
+		v.Set("with-local-disks", "1")
+	}
+	if o.TargetStorage != "" {
+		v.Set("targetstorage", o.TargetStorage)
+	}
+	if o.MigrationNetwork != "" {
+		v.Set("migration_network", o.MigrationNetwork)
+	}
+	if o.MigrationType != "" {
+		v.Set("migration_type", o.MigrationType)
+	}
+	return v
+}
+
+// RebootVm reboots the guest by shutting it down and starting it again;
+// opts.Timeout bounds how long to wait for the shutdown.
+func (c *Client) RebootVm(vmr *VmRef, opts VMRebootOptions) error {
+	return c.RebootVmCtx(context.Background(), vmr, opts)
+}
+
+// RebootVmCtx is the context-aware variant of RebootVm.
+func (c *Client) RebootVmCtx(ctx context.Context, vmr *VmRef, opts VMRebootOptions) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/status/reboot", vmr.node, vmr.VmId), opts.values())
+}
+
+// ResumeVm resumes a previously suspended guest.
+func (c *Client) ResumeVm(vmr *VmRef, opts VMResumeOptions) error {
+	return c.ResumeVmCtx(context.Background(), vmr, opts)
+}
+
+// ResumeVmCtx is the context-aware variant of ResumeVm.
+func (c *Client) ResumeVmCtx(ctx context.Context, vmr *VmRef, opts VMResumeOptions) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/status/resume", vmr.node, vmr.VmId), opts.values())
+}
+
+// ShutdownVm asks the guest's ACPI to power off, optionally forcing a hard
+// stop after Timeout.
+func (c *Client) ShutdownVm(vmr *VmRef, opts VMShutdownOptions) error {
+	return c.ShutdownVmCtx(context.Background(), vmr, opts)
+}
+
+// ShutdownVmCtx is the context-aware variant of ShutdownVm.
+func (c *Client) ShutdownVmCtx(ctx context.Context, vmr *VmRef, opts VMShutdownOptions) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/status/shutdown", vmr.node, vmr.VmId), opts.values())
+}
+
+// MigrateVm migrates the guest to opts.Target, live if opts.Online is set.
+func (c *Client) MigrateVm(vmr *VmRef, opts VMMigrateOptions) error {
+	return c.MigrateVmCtx(context.Background(), vmr, opts)
+}
+
+// MigrateVmCtx is the context-aware variant of MigrateVm.
+func (c *Client) MigrateVmCtx(ctx context.Context, vmr *VmRef, opts VMMigrateOptions) error {
+	return c.postFormCtx(ctx, fmt.Sprintf("/nodes/%s/qemu/%d/migrate", vmr.node, vmr.VmId), opts.values())
+}